@@ -7,16 +7,35 @@
 package untappd
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
+	"os"
 	"time"
 )
 
 const (
 	// jsonContentType is the content type for JSON data
 	jsonContentType = "application/json"
+
+	// untappdUserAgent is sent with every request, to identify the library
+	// making the calls to the API
+	untappdUserAgent = "github.com/mdlayher/untappd"
+)
+
+// Errors returned when constructing a Client without the required client
+// ID and/or client secret.
+var (
+	// ErrNoClientID is returned when no client ID is specified.
+	ErrNoClientID = errors.New("no client ID specified")
+
+	// ErrNoClientSecret is returned when no client secret is specified.
+	ErrNoClientSecret = errors.New("no client secret specified")
 )
 
 // Client is a HTTP client for the Untappd APIv4.  It enables access to various
@@ -27,8 +46,15 @@ type Client struct {
 
 	clientID     string
 	clientSecret string
+	accessToken  string
 
 	userAgent string
+
+	rl    *rateLimitBox
+	retry *RetryPolicy
+
+	logger Logger
+	debug  bool
 }
 
 // NewClient creates a properly initialized instance of Client, using the input
@@ -37,6 +63,20 @@ type Client struct {
 // To use a Client with the Untappd APIv4, you must register for an API key
 // here: https://untappd.com/api/register.
 func NewClient(clientID string, clientSecret string, client *http.Client) (*Client, error) {
+	if clientID == "" {
+		return nil, ErrNoClientID
+	}
+	if clientSecret == "" {
+		return nil, ErrNoClientSecret
+	}
+
+	return newClient(clientID, clientSecret, client), nil
+}
+
+// newClient creates a Client without validating clientID and clientSecret,
+// for use by constructors such as NewAuthenticatedClient which authenticate
+// using an access token instead and so have no client ID or secret to check.
+func newClient(clientID string, clientSecret string, client *http.Client) *Client {
 	// If input client is nil, use http.DefaultClient
 	if client == nil {
 		client = http.DefaultClient
@@ -56,17 +96,47 @@ func NewClient(clientID string, clientSecret string, client *http.Client) (*Clie
 
 		// For now, user agent simply indicates the library making the
 		// calls to the API
-		userAgent: "github.com/mdlayher/untappd",
+		userAgent: untappdUserAgent,
+
+		rl: &rateLimitBox{},
 	}
 
-	return c, nil
+	// Mirror linodego's envDebug hook: enable debug logging to stderr when
+	// requested via the environment, without requiring a code change
+	if os.Getenv(envDebug) != "" {
+		c = c.WithDebug(true)
+	}
+
+	return c
+}
+
+// NewAuthenticatedClient creates a properly initialized instance of Client,
+// using the input OAuth2 user access token and http.Client.
+//
+// An authenticated Client can access user-scoped methods, such as checkin,
+// toast, comment, and the authenticated "user/info" endpoint, which are not
+// reachable using a client ID and client secret alone.  An access token can
+// be obtained using the oauth subpackage's 3-legged authentication flow.
+func NewAuthenticatedClient(accessToken string, client *http.Client) (*Client, error) {
+	return newClient("", "", client).WithAccessToken(accessToken), nil
+}
+
+// WithAccessToken creates a shallow copy of Client which authenticates using
+// the input OAuth2 user access token, instead of a client ID and client
+// secret.  Per Untappd's API rules, the access_token and client_id/
+// client_secret authentication schemes are mutually exclusive; when an
+// access token is set, it is sent instead of the client ID and secret.
+func (c *Client) WithAccessToken(accessToken string) *Client {
+	cc := *c
+	cc.accessToken = accessToken
+	return &cc
 }
 
 // Error represents an error returned from the Untappd APIv4.
 type Error struct {
 	Code              int
 	Detail            string
-	Type              string
+	Type              ErrorType
 	DeveloperFriendly string
 	Duration          time.Duration
 }
@@ -83,8 +153,52 @@ func (e Error) Error() string {
 	return fmt.Sprintf("%d [%s]: %s", e.Code, e.Type, details)
 }
 
-// request creates a new HTTP request, using the specified HTTP method and API endpoint.
-func (c *Client) request(method string, endpoint string, query url.Values, v interface{}) (*http.Response, error) {
+// request creates a new HTTP request, using the specified HTTP method and API
+// endpoint.  The request is bound to ctx, so callers can cancel it or attach
+// a deadline; cancellation also interrupts decoding of the response body.
+//
+// If c has a RetryPolicy configured via WithRetry, request automatically
+// retries using exponential backoff with jitter when the policy's
+// ShouldRetry reports true, honoring any X-Ratelimit-Expired reset time seen
+// on a 429 response.  GET requests are retried by default; other methods are
+// only retried if the policy opts in via RetryNonIdempotent.
+func (c *Client) request(ctx context.Context, method string, endpoint string, query url.Values, v interface{}) (*http.Response, error) {
+	policy := c.retry
+	if policy == nil || (method != http.MethodGet && !policy.RetryNonIdempotent) {
+		return c.do(ctx, method, endpoint, query, v)
+	}
+
+	shouldRetry := policy.ShouldRetry
+	if shouldRetry == nil {
+		shouldRetry = DefaultShouldRetry
+	}
+
+	maxAttempts := policy.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	var res *http.Response
+	var err error
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		res, err = c.do(ctx, method, endpoint, query, v)
+		if attempt == maxAttempts-1 || !shouldRetry(res, err) {
+			return res, err
+		}
+
+		if sErr := sleep(ctx, backoff(*policy, attempt, res)); sErr != nil {
+			return res, sErr
+		}
+	}
+
+	return res, err
+}
+
+// do performs a single HTTP request attempt, using the specified HTTP
+// method and API endpoint.  The request is bound to ctx, so callers can
+// cancel it or attach a deadline; cancellation also interrupts decoding of
+// the response body.
+func (c *Client) do(ctx context.Context, method string, endpoint string, query url.Values, v interface{}) (*http.Response, error) {
 	// Generate relative URL using API root and endpoint
 	rel, err := url.Parse(fmt.Sprintf("%s/%s/", c.url.Path, endpoint))
 	if err != nil {
@@ -102,13 +216,20 @@ func (c *Client) request(method string, endpoint string, query url.Values, v int
 		}
 	}
 
-	// Add required client ID and client secret
-	q.Set("client_id", c.clientID)
-	q.Set("client_secret", c.clientSecret)
+	// Authenticate using either an OAuth2 access token, or a client ID and
+	// client secret; Untappd treats the two schemes as mutually exclusive, so
+	// prefer the access token when one is configured
+	if c.accessToken != "" {
+		q.Set("access_token", c.accessToken)
+	} else {
+		q.Set("client_id", c.clientID)
+		q.Set("client_secret", c.clientSecret)
+	}
 	u.RawQuery = q.Encode()
 
-	// Generate new HTTP request for appropriate URL
-	req, err := http.NewRequest(method, u.String(), nil)
+	// Generate new HTTP request for appropriate URL, bound to ctx so that
+	// c.client.Do below can be interrupted by cancellation or a deadline
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -120,6 +241,14 @@ func (c *Client) request(method string, endpoint string, query url.Values, v int
 	// Identify the client
 	req.Header.Add("User-Agent", c.userAgent)
 
+	if c.debug && c.logger != nil {
+		c.logger.LogRequest(RequestLog{
+			Method: req.Method,
+			URL:    redactURL(req.URL.String()),
+			Header: req.Header,
+		})
+	}
+
 	// Invoke request using underlying HTTP client
 	res, err := c.client.Do(req)
 	if err != nil {
@@ -127,6 +256,26 @@ func (c *Client) request(method string, endpoint string, query url.Values, v int
 	}
 	defer res.Body.Close()
 
+	if c.debug && c.logger != nil {
+		// Buffer the body so it can be logged, then replace it with a
+		// fresh reader so checkResponse and the JSON decoder below can
+		// still consume it normally
+		body, err := io.ReadAll(res.Body)
+		if err != nil {
+			return res, err
+		}
+		res.Body = io.NopCloser(bytes.NewReader(body))
+
+		c.logger.LogResponse(ResponseLog{
+			StatusCode: res.StatusCode,
+			Header:     res.Header,
+			Body:       body,
+		})
+	}
+
+	// Record the rate limit state reported by this response, if any
+	c.setRateLimit(res)
+
 	// Check response for errors
 	if err := checkResponse(res); err != nil {
 		return res, err
@@ -137,7 +286,10 @@ func (c *Client) request(method string, endpoint string, query url.Values, v int
 		return res, nil
 	}
 
-	// Decode response body into v, returning response
+	// Decode response body into v.  req was created with
+	// http.NewRequestWithContext, so the Transport already aborts the
+	// in-flight read (and this Decode call returns ctx.Err()) if ctx is
+	// canceled or its deadline expires before the body finishes arriving.
 	return res, json.NewDecoder(res.Body).Decode(v)
 }
 
@@ -158,14 +310,11 @@ func checkResponse(res *http.Response) error {
 	// a more consumable form on error output
 	var apiErr struct {
 		Meta struct {
-			Code              int    `json:"code"`
-			ErrorDetail       string `json:"error_detail"`
-			ErrorType         string `json:"error_type"`
-			DeveloperFriendly string `json:"developer_friendly"`
-			ResponseTime      struct {
-				Time    float64 `json:"time"`
-				Measure string  `json:"measure"`
-			} `json:"response_time"`
+			Code              int          `json:"code"`
+			ErrorDetail       string       `json:"error_detail"`
+			ErrorType         string       `json:"error_type"`
+			DeveloperFriendly string       `json:"developer_friendly"`
+			ResponseTime      responseTime `json:"response_time"`
 		} `json:"meta"`
 	}
 
@@ -174,22 +323,44 @@ func checkResponse(res *http.Response) error {
 		return err
 	}
 
+	// Determine the error type, falling back to a classification based on
+	// the meta block's status code when the APIv4 didn't supply an error_type
+	eType := ErrorType(apiErr.Meta.ErrorType)
+	if eType == "" {
+		eType = errorTypeFromCode(apiErr.Meta.Code)
+	}
+
 	// Assemble Error struct from API response
 	return &Error{
 		Code:              apiErr.Meta.Code,
 		Detail:            apiErr.Meta.ErrorDetail,
-		Type:              apiErr.Meta.ErrorType,
+		Type:              eType,
 		DeveloperFriendly: apiErr.Meta.DeveloperFriendly,
-		Duration: timeUnitToDuration(
-			apiErr.Meta.ResponseTime.Time,
-			apiErr.Meta.ResponseTime.Measure,
-		),
+		Duration:          time.Duration(apiErr.Meta.ResponseTime),
 	}
 }
 
-// timeUnitToDuration parses a time float64 and measure string from the Untappd
-// APIv4, and converts them into a native Go time.Duration.
-func timeUnitToDuration(timeFloat float64, measure string) time.Duration {
+// errInvalidTimeUnit is returned when a responseTime's "measure" field names
+// a unit the Untappd APIv4 is not known to send.
+var errInvalidTimeUnit = errors.New("untappd: invalid response time unit")
+
+// responseTime is a time.Duration which knows how to unmarshal the
+// "response_time" block of an Untappd APIv4 response, which reports a
+// duration as a separate float64 value and unit string rather than as a
+// single Go-parseable value.
+type responseTime time.Duration
+
+// UnmarshalJSON unmarshals a responseTime from its "time"/"measure" JSON
+// representation.
+func (r *responseTime) UnmarshalJSON(b []byte) error {
+	var v struct {
+		Time    float64 `json:"time"`
+		Measure string  `json:"measure"`
+	}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+
 	// Known measure strings mapped to Go parse-able equivalents
 	timeUnits := map[string]string{
 		"milliseconds": "ms",
@@ -197,19 +368,17 @@ func timeUnitToDuration(timeFloat float64, measure string) time.Duration {
 		"minutes":      "m",
 	}
 
-	// Verify a known time unit is used
-	timeUnit, ok := timeUnits[measure]
+	timeUnit, ok := timeUnits[v.Measure]
 	if !ok {
-		// If unknown, return no duration
-		return 0
+		return errInvalidTimeUnit
 	}
 
 	// Parse a Go time.Duration from string
-	duration, err := time.ParseDuration(fmt.Sprintf("%f%s", timeFloat, timeUnit))
+	duration, err := time.ParseDuration(fmt.Sprintf("%f%s", v.Time, timeUnit))
 	if err != nil {
-		// If error, return no duration
-		return 0
+		return err
 	}
 
-	return duration
-}
\ No newline at end of file
+	*r = responseTime(duration)
+	return nil
+}
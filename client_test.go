@@ -2,6 +2,7 @@ package untappd
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"io"
 	"io/ioutil"
@@ -39,7 +40,7 @@ func TestErrorError(t *testing.T) {
 	var tests = []struct {
 		description string
 		code        int
-		eType       string
+		eType       ErrorType
 		details     string
 		developer   string
 		result      string
@@ -104,7 +105,7 @@ func TestClient_requestContainsAPIKeys(t *testing.T) {
 	})
 	defer done()
 
-	if _, err := c.request(method, "foo", nil, nil); err != nil {
+	if _, err := c.request(context.Background(), method, "foo", nil, nil); err != nil {
 		t.Fatal(err)
 	}
 }
@@ -139,7 +140,7 @@ func TestClient_requestContainsQueryParameters(t *testing.T) {
 	})
 	defer done()
 
-	if _, err := c.request(method, "foo", url.Values{
+	if _, err := c.request(context.Background(), method, "foo", url.Values{
 		"foo": []string{"bar"},
 		"bar": []string{"baz"},
 		"baz": []string{"qux", "corge"},
@@ -172,7 +173,7 @@ func TestClient_requestContainsHeaders(t *testing.T) {
 	})
 	defer done()
 
-	if _, err := c.request(method, "foo", nil, nil); err != nil {
+	if _, err := c.request(context.Background(), method, "foo", nil, nil); err != nil {
 		t.Fatal(err)
 	}
 }
@@ -198,7 +199,7 @@ func TestClient_requestContainsBody(t *testing.T) {
 		} `json:"meta"`
 	}
 
-	if _, err := c.request(method, "foo", nil, &v); err != nil {
+	if _, err := c.request(context.Background(), method, "foo", nil, &v); err != nil {
 		t.Fatal(err)
 	}
 
@@ -368,6 +369,107 @@ func Test_responseTimeUnmarshalJSON(t *testing.T) {
 	}
 }
 
+// TestClient_requestAuthModes verifies that requests sent via a Client
+// configured with a client ID/secret emit client_id/client_secret, while a
+// Client configured via WithAccessToken emits access_token instead.
+func TestClient_requestAuthModes(t *testing.T) {
+	var tests = []struct {
+		description string
+		accessToken string
+	}{
+		{"client ID and secret", ""},
+		{"access token", "deadbeef"},
+	}
+
+	for _, tt := range tests {
+		c, done := testClient(t, func(t *testing.T, w http.ResponseWriter, r *http.Request) {
+			q := r.URL.Query()
+
+			if tt.accessToken == "" {
+				if q.Get("client_id") == "" {
+					t.Fatalf("%s: empty client_id query parameter", tt.description)
+				}
+				if q.Get("client_secret") == "" {
+					t.Fatalf("%s: empty client_secret query parameter", tt.description)
+				}
+				if q.Get("access_token") != "" {
+					t.Fatalf("%s: unexpected access_token query parameter", tt.description)
+				}
+				return
+			}
+
+			if s := q.Get("access_token"); s != tt.accessToken {
+				t.Fatalf("%s: unexpected access_token: %q != %q", tt.description, s, tt.accessToken)
+			}
+			if q.Get("client_id") != "" {
+				t.Fatalf("%s: unexpected client_id query parameter", tt.description)
+			}
+			if q.Get("client_secret") != "" {
+				t.Fatalf("%s: unexpected client_secret query parameter", tt.description)
+			}
+		})
+		defer done()
+
+		if tt.accessToken != "" {
+			c = c.WithAccessToken(tt.accessToken)
+		}
+
+		if _, err := c.request(context.Background(), "GET", "foo", nil, nil); err != nil {
+			t.Fatalf("%s: %v", tt.description, err)
+		}
+	}
+}
+
+// TestClient_requestContextDeadlineExceeded verifies that request returns
+// context.DeadlineExceeded when the context deadline is reached before the
+// server responds.
+func TestClient_requestContextDeadlineExceeded(t *testing.T) {
+	block := make(chan struct{})
+
+	c, done := testClient(t, func(t *testing.T, w http.ResponseWriter, r *http.Request) {
+		<-block
+	})
+	defer done()
+
+	// close(block) must run before done(), which closes the httptest.Server:
+	// Server.Close blocks until the in-flight handler goroutine returns, and
+	// that goroutine is parked on <-block until this unblocks it.
+	defer close(block)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := c.request(ctx, "GET", "foo", nil, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("unexpected error: %v != %v", err, context.DeadlineExceeded)
+	}
+}
+
+// TestClient_requestContextCanceled verifies that request returns
+// context.Canceled when the context is canceled before the server responds.
+func TestClient_requestContextCanceled(t *testing.T) {
+	block := make(chan struct{})
+
+	c, done := testClient(t, func(t *testing.T, w http.ResponseWriter, r *http.Request) {
+		<-block
+	})
+	defer done()
+
+	// close(block) must run before done(), which closes the httptest.Server:
+	// Server.Close blocks until the in-flight handler goroutine returns, and
+	// that goroutine is parked on <-block until this unblocks it.
+	defer close(block)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+
+	if _, err := c.request(ctx, "GET", "foo", nil, nil); !errors.Is(err, context.Canceled) {
+		t.Fatalf("unexpected error: %v != %v", err, context.Canceled)
+	}
+}
+
 // withHTTPResponse is a test helper which generates a *http.Response and invokes
 // an input closure, used for testing.
 func withHTTPResponse(t *testing.T, code int, contentType string, body []byte, fn func(t *testing.T, res *http.Response)) {
@@ -430,10 +532,14 @@ func assertInvalidUserErr(t *testing.T, err error) {
 	if d := uErr.Detail; d != detail {
 		t.Fatalf("unexpected error detail: %q != %q", d, detail)
 	}
-	eType := "invalid_user"
+	eType := ErrorTypeInvalidUser
 	if e := uErr.Type; e != eType {
 		t.Fatalf("unexpected error type: %q != %q", e, eType)
 	}
+
+	if !errors.Is(err, ErrInvalidUser) {
+		t.Fatal("errors.Is(err, ErrInvalidUser) == false")
+	}
 }
 
 // JSON taken from Untappd APIv4 documentation: https://untappd.com/api/docs
@@ -0,0 +1,101 @@
+package untappd
+
+import "net/http"
+
+// ErrorType identifies the "error_type" value returned in the "meta" block
+// of an Untappd APIv4 error response.
+type ErrorType string
+
+// Known ErrorType values returned by the Untappd APIv4.  This list is not
+// exhaustive; unrecognized error_type strings are preserved verbatim in
+// Error.Type, and responses which omit error_type entirely are classified
+// by errorTypeFromCode based on the status code in their "meta" block.
+const (
+	ErrorTypeInvalidAuth     ErrorType = "invalid_auth"
+	ErrorTypeInvalidToken    ErrorType = "invalid_token"
+	ErrorTypeInvalidUser     ErrorType = "invalid_user"
+	ErrorTypeInvalidLimit    ErrorType = "invalid_limit"
+	ErrorTypeInvalidParam    ErrorType = "invalid_param"
+	ErrorTypeNotFound        ErrorType = "not_found"
+	ErrorTypeEndpointRemoved ErrorType = "endpoint_removed"
+)
+
+// Sentinel errors for the ErrorType values above, so callers can use
+// errors.Is(err, untappd.ErrInvalidUser) instead of comparing
+// Error.Type against a string directly.
+var (
+	// ErrInvalidAuth is returned when the client ID/secret or access token
+	// is missing, malformed, or otherwise rejected by the Untappd APIv4.
+	ErrInvalidAuth = sentinel(ErrorTypeInvalidAuth)
+
+	// ErrInvalidToken is returned when an OAuth2 access token is expired,
+	// revoked, or otherwise no longer valid.
+	ErrInvalidToken = sentinel(ErrorTypeInvalidToken)
+
+	// ErrInvalidUser is returned when a request references a Untappd
+	// username which does not exist.
+	ErrInvalidUser = sentinel(ErrorTypeInvalidUser)
+
+	// ErrInvalidLimit is returned when the per-hour API quota for the
+	// calling client ID or access token has been exhausted.
+	ErrInvalidLimit = sentinel(ErrorTypeInvalidLimit)
+
+	// ErrInvalidParam is returned when a request supplies a malformed or
+	// out-of-range parameter.
+	ErrInvalidParam = sentinel(ErrorTypeInvalidParam)
+
+	// ErrNotFound is returned when the requested resource does not exist.
+	ErrNotFound = sentinel(ErrorTypeNotFound)
+
+	// ErrEndpointRemoved is returned when a request targets an endpoint
+	// which has been removed from the Untappd APIv4.
+	ErrEndpointRemoved = sentinel(ErrorTypeEndpointRemoved)
+)
+
+// sentinelsByType maps each known ErrorType to its sentinel error, used by
+// Error.Is to implement errors.Is support.
+var sentinelsByType = map[ErrorType]error{
+	ErrorTypeInvalidAuth:     ErrInvalidAuth,
+	ErrorTypeInvalidToken:    ErrInvalidToken,
+	ErrorTypeInvalidUser:     ErrInvalidUser,
+	ErrorTypeInvalidLimit:    ErrInvalidLimit,
+	ErrorTypeInvalidParam:    ErrInvalidParam,
+	ErrorTypeNotFound:        ErrNotFound,
+	ErrorTypeEndpointRemoved: ErrEndpointRemoved,
+}
+
+// sentinelError is a trivial error implementation used to construct the
+// package's sentinel error values above.
+type sentinelError string
+
+func (e sentinelError) Error() string { return string(e) }
+
+// sentinel constructs the sentinel error for an ErrorType.
+func sentinel(t ErrorType) error {
+	return sentinelError(t)
+}
+
+// Is reports whether target is one of the package's sentinel errors and
+// matches e's Type, so that callers can use errors.Is(err,
+// untappd.ErrInvalidUser) rather than comparing e.Type against a string.
+func (e *Error) Is(target error) bool {
+	want, ok := sentinelsByType[e.Type]
+	return ok && target == want
+}
+
+// errorTypeFromCode classifies an HTTP status code into an ErrorType, for
+// use when an Untappd APIv4 error response omits error_type.
+func errorTypeFromCode(code int) ErrorType {
+	switch code {
+	case http.StatusNotFound:
+		return ErrorTypeNotFound
+	case http.StatusGone:
+		return ErrorTypeEndpointRemoved
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ErrorTypeInvalidAuth
+	case http.StatusTooManyRequests:
+		return ErrorTypeInvalidLimit
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,92 @@
+package untappd
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+// TestError_Is verifies that errors.Is correctly matches an *Error against
+// the package's sentinel errors, for both the canned apiErrJSON and
+// invalidUserErrJSON fixtures.
+func TestError_Is(t *testing.T) {
+	var tests = []struct {
+		description string
+		body        []byte
+		want        error
+		wantNot     []error
+	}{
+		{
+			description: "apiErrJSON is invalid_auth",
+			body:        apiErrJSON,
+			want:        ErrInvalidAuth,
+			wantNot:     []error{ErrInvalidUser, ErrNotFound},
+		},
+		{
+			description: "invalidUserErrJSON is invalid_user",
+			body:        invalidUserErrJSON,
+			want:        ErrInvalidUser,
+			wantNot:     []error{ErrInvalidAuth, ErrInvalidLimit},
+		},
+	}
+
+	for _, tt := range tests {
+		withHTTPResponse(t, http.StatusInternalServerError, jsonContentType, tt.body, func(t *testing.T, res *http.Response) {
+			err := checkResponse(res)
+			if err == nil {
+				t.Fatalf("%s: expected an error, but none occurred", tt.description)
+			}
+
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("%s: errors.Is(err, %v) == false", tt.description, tt.want)
+			}
+
+			for _, notWant := range tt.wantNot {
+				if errors.Is(err, notWant) {
+					t.Fatalf("%s: errors.Is(err, %v) == true, want false", tt.description, notWant)
+				}
+			}
+
+			var uErr *Error
+			if !errors.As(err, &uErr) {
+				t.Fatalf("%s: errors.As(err, &uErr) == false", tt.description)
+			}
+		})
+	}
+}
+
+// TestErrorTypeFromCode verifies that errorTypeFromCode classifies known
+// HTTP status codes, and leaves unrecognized codes uncategorized.
+func TestErrorTypeFromCode(t *testing.T) {
+	var tests = []struct {
+		code int
+		want ErrorType
+	}{
+		{http.StatusNotFound, ErrorTypeNotFound},
+		{http.StatusGone, ErrorTypeEndpointRemoved},
+		{http.StatusUnauthorized, ErrorTypeInvalidAuth},
+		{http.StatusForbidden, ErrorTypeInvalidAuth},
+		{http.StatusTooManyRequests, ErrorTypeInvalidLimit},
+		{http.StatusTeapot, ""},
+	}
+
+	for _, tt := range tests {
+		if got := errorTypeFromCode(tt.code); got != tt.want {
+			t.Fatalf("errorTypeFromCode(%d): unexpected result: %q != %q", tt.code, got, tt.want)
+		}
+	}
+}
+
+// TestCheckResponseMissingErrorTypeFallsBackToCode verifies that
+// checkResponse classifies an error response missing error_type using the
+// status code in its "meta" block.
+func TestCheckResponseMissingErrorTypeFallsBackToCode(t *testing.T) {
+	body := []byte(`{"meta":{"code":404,"error_detail":"Invalid user."}}`)
+
+	withHTTPResponse(t, http.StatusInternalServerError, jsonContentType, body, func(t *testing.T, res *http.Response) {
+		err := checkResponse(res)
+		if !errors.Is(err, ErrNotFound) {
+			t.Fatalf("errors.Is(err, ErrNotFound) == false: %v", err)
+		}
+	})
+}
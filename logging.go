@@ -0,0 +1,102 @@
+package untappd
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+)
+
+// envDebug is the environment variable which, when set to a non-empty
+// value, enables debug logging on any Client created via NewClient, mirrors
+// linodego's envDebug hook.
+const envDebug = "UNTAPPD_DEBUG"
+
+// redactedParams holds query parameter names whose values are replaced with
+// "REDACTED" before being handed to a Logger, so credentials are never
+// written to logs.
+var redactedParams = []string{"client_secret", "access_token"}
+
+// RequestLog describes an outgoing HTTP request, passed to Logger.LogRequest.
+type RequestLog struct {
+	Method string
+	URL    string
+	Header http.Header
+	Body   []byte
+}
+
+// ResponseLog describes an HTTP response, passed to Logger.LogResponse.
+type ResponseLog struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// Logger receives request and response details from a Client configured
+// via Client.WithLogger, for debugging API interactions.
+type Logger interface {
+	LogRequest(RequestLog)
+	LogResponse(ResponseLog)
+}
+
+// StdLogger is a Logger which writes human-readable request and response
+// details to an io.Writer.
+type StdLogger struct {
+	w io.Writer
+}
+
+// NewStdLogger creates a StdLogger which writes to w.
+func NewStdLogger(w io.Writer) *StdLogger {
+	return &StdLogger{w: w}
+}
+
+// LogRequest implements Logger.
+func (l *StdLogger) LogRequest(r RequestLog) {
+	fmt.Fprintf(l.w, "--> %s %s\n%s%s\n", r.Method, r.URL, r.Header, r.Body)
+}
+
+// LogResponse implements Logger.
+func (l *StdLogger) LogResponse(r ResponseLog) {
+	fmt.Fprintf(l.w, "<-- %d\n%s%s\n", r.StatusCode, r.Header, r.Body)
+}
+
+// WithLogger creates a shallow copy of Client which reports request and
+// response details to logger whenever debug logging is enabled.  Use
+// together with WithDebug.
+func (c *Client) WithLogger(logger Logger) *Client {
+	cc := *c
+	cc.logger = logger
+	return &cc
+}
+
+// WithDebug creates a shallow copy of Client with debug logging enabled or
+// disabled.  When enabled without a Logger configured via WithLogger, a
+// StdLogger writing to os.Stderr is used.
+func (c *Client) WithDebug(debug bool) *Client {
+	cc := *c
+	cc.debug = debug
+	if debug && cc.logger == nil {
+		cc.logger = NewStdLogger(os.Stderr)
+	}
+	return &cc
+}
+
+// redactURL returns a copy of rawurl with redactedParams query values
+// replaced with "REDACTED", so secrets are never written to logs.
+func redactURL(rawurl string) string {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return rawurl
+	}
+
+	q := u.Query()
+	for _, p := range redactedParams {
+		if q.Get(p) != "" {
+			q.Set(p, "REDACTED")
+		}
+	}
+	u.RawQuery = q.Encode()
+
+	return u.String()
+}
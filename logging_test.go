@@ -0,0 +1,71 @@
+package untappd
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// capturingLogger is a Logger which records every RequestLog/ResponseLog it
+// receives, for use in tests.
+type capturingLogger struct {
+	requests  []RequestLog
+	responses []ResponseLog
+}
+
+func (l *capturingLogger) LogRequest(r RequestLog)   { l.requests = append(l.requests, r) }
+func (l *capturingLogger) LogResponse(r ResponseLog) { l.responses = append(l.responses, r) }
+
+// TestClient_requestLogsRedactCredentials verifies that a Client configured
+// with WithLogger and WithDebug records the request and response, with the
+// access_token query value redacted in the logged URL.
+func TestClient_requestLogsRedactCredentials(t *testing.T) {
+	c, done := testClient(t, func(t *testing.T, w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"hello":"world"}`))
+	})
+	defer done()
+
+	logger := &capturingLogger{}
+	c = c.WithLogger(logger).WithDebug(true).WithAccessToken("secret-token")
+
+	if _, err := c.request(context.Background(), "GET", "foo", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(logger.requests) != 1 {
+		t.Fatalf("unexpected number of logged requests: %d != %d", len(logger.requests), 1)
+	}
+	if len(logger.responses) != 1 {
+		t.Fatalf("unexpected number of logged responses: %d != %d", len(logger.responses), 1)
+	}
+
+	loggedURL := logger.requests[0].URL
+	if strings.Contains(loggedURL, "secret-token") {
+		t.Fatalf("logged URL leaked access_token: %q", loggedURL)
+	}
+	if !strings.Contains(loggedURL, "access_token=REDACTED") {
+		t.Fatalf("logged URL missing redacted access_token: %q", loggedURL)
+	}
+
+	if body := string(logger.responses[0].Body); !strings.Contains(body, "hello") {
+		t.Fatalf("unexpected logged response body: %q", body)
+	}
+}
+
+// TestRedactURL verifies that redactURL replaces the client_secret query
+// value, while leaving other parameters untouched.
+func TestRedactURL(t *testing.T) {
+	in := "https://api.untappd.com/v4/foo?client_id=abc&client_secret=shh&foo=bar"
+	out := redactURL(in)
+
+	if strings.Contains(out, "shh") {
+		t.Fatalf("redactURL leaked client_secret: %q", out)
+	}
+	if !strings.Contains(out, "client_id=abc") {
+		t.Fatalf("redactURL altered unrelated parameter: %q", out)
+	}
+	if !strings.Contains(out, "foo=bar") {
+		t.Fatalf("redactURL altered unrelated parameter: %q", out)
+	}
+}
@@ -0,0 +1,89 @@
+// Package oauth implements the Untappd APIv4's 3-legged, server-side OAuth2
+// authentication flow, used to obtain a user access token for use with
+// untappd.Client.WithAccessToken.
+//
+// For details on the flow implemented by this package, see:
+// https://untappd.com/api/docs#authentication.
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// Overridable as vars, rather than consts, so tests can point them at a
+// local httptest server.
+var (
+	authenticateURL = "https://untappd.com/oauth/authenticate/"
+	authorizeURL    = "https://untappd.com/oauth/authorize/"
+)
+
+// AuthCodeURL builds the URL that a user must be redirected to in order to
+// begin the Untappd OAuth2 flow.  redirectURI must exactly match the
+// redirect URL registered for clientID, and state is an opaque value used
+// to prevent CSRF attacks, round-tripped back to redirectURI unmodified.
+func AuthCodeURL(clientID, redirectURI, state string) string {
+	q := url.Values{
+		"client_id":     []string{clientID},
+		"response_type": []string{"code"},
+		"redirect_url":  []string{redirectURI},
+	}
+	if state != "" {
+		q.Set("state", state)
+	}
+
+	return authenticateURL + "?" + q.Encode()
+}
+
+// Exchange trades the authorization code returned to redirectURI for a user
+// access token, by invoking the Untappd APIv4's token endpoint.  The
+// resulting access token can be used with untappd.Client.WithAccessToken.
+func Exchange(ctx context.Context, client *http.Client, clientID, clientSecret, code, redirectURI string) (string, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	q := url.Values{
+		"client_id":     []string{clientID},
+		"client_secret": []string{clientSecret},
+		"response_type": []string{"code"},
+		"redirect_url":  []string{redirectURI},
+		"code":          []string{code},
+	}
+
+	u := authorizeURL + "?" + q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return "", err
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	var v struct {
+		Response struct {
+			AccessToken string `json:"access_token"`
+		} `json:"response"`
+		Meta struct {
+			Code      int    `json:"code"`
+			ErrorType string `json:"error_type"`
+		} `json:"meta"`
+	}
+
+	if err := json.NewDecoder(res.Body).Decode(&v); err != nil {
+		return "", err
+	}
+
+	if v.Meta.Code != http.StatusOK {
+		return "", fmt.Errorf("oauth: exchange failed with %d [%s]", v.Meta.Code, v.Meta.ErrorType)
+	}
+
+	return v.Response.AccessToken, nil
+}
@@ -0,0 +1,63 @@
+package oauth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// TestAuthCodeURL verifies that AuthCodeURL produces a URL pointing at the
+// Untappd authenticate endpoint, with all expected query parameters set.
+func TestAuthCodeURL(t *testing.T) {
+	u := AuthCodeURL("client-id", "https://example.com/callback", "xyz")
+
+	if !strings.HasPrefix(u, authenticateURL) {
+		t.Fatalf("unexpected URL prefix: %q", u)
+	}
+
+	parsed, err := url.Parse(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	q := parsed.Query()
+	if s := q.Get("client_id"); s != "client-id" {
+		t.Fatalf("unexpected client_id: %q != %q", s, "client-id")
+	}
+	if s := q.Get("redirect_url"); s != "https://example.com/callback" {
+		t.Fatalf("unexpected redirect_url: %q != %q", s, "https://example.com/callback")
+	}
+	if s := q.Get("state"); s != "xyz" {
+		t.Fatalf("unexpected state: %q != %q", s, "xyz")
+	}
+}
+
+// TestExchange verifies that Exchange parses an access token out of a
+// successful token endpoint response.
+func TestExchange(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if s := q.Get("code"); s != "abc123" {
+			t.Fatalf("unexpected code: %q != %q", s, "abc123")
+		}
+
+		w.Write([]byte(`{"meta":{"code":200},"response":{"access_token":"deadbeef"}}`))
+	}))
+	defer srv.Close()
+
+	orig := authorizeURL
+	authorizeURL = srv.URL
+	defer func() { authorizeURL = orig }()
+
+	token, err := Exchange(context.Background(), nil, "client-id", "client-secret", "abc123", "https://example.com/callback")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if token != "deadbeef" {
+		t.Fatalf("unexpected access token: %q != %q", token, "deadbeef")
+	}
+}
@@ -0,0 +1,192 @@
+package untappd
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// PageInfo describes the pagination state of an Untappd APIv4 list
+// endpoint, as reported by the "pagination" block of a list response.
+type PageInfo struct {
+	// NextURL is the full URL of the next page, as returned by the
+	// Untappd APIv4.  When empty, the next page must be synthesized from
+	// MaxID or Offset instead.
+	NextURL string
+
+	// MaxID is the highest ID seen so far, used by some endpoints (such
+	// as checkin feeds) to request the next page of older results.
+	MaxID int
+
+	// Offset is the number of items to skip, used by endpoints which
+	// paginate via offset/limit rather than max_id.
+	Offset int
+
+	// PageToken is an opaque, serializable encoding of this PageInfo's
+	// MaxID and Offset, which callers can store and later pass to
+	// ParsePageToken to resume iteration from this point via
+	// NewIteratorFromPage.
+	PageToken string
+}
+
+// ParsePageToken parses a PageToken produced by a previous PageInfo back
+// into a PageInfo, so that iteration can be resumed from that point using
+// NewIteratorFromPage.
+func ParsePageToken(token string) (PageInfo, error) {
+	maxID, offset, ok := strings.Cut(token, ":")
+	if !ok {
+		return PageInfo{}, fmt.Errorf("untappd: malformed page token %q", token)
+	}
+
+	pi := PageInfo{PageToken: token}
+
+	var err error
+	if pi.MaxID, err = strconv.Atoi(maxID); err != nil {
+		return PageInfo{}, fmt.Errorf("untappd: malformed page token %q: %w", token, err)
+	}
+	if pi.Offset, err = strconv.Atoi(offset); err != nil {
+		return PageInfo{}, fmt.Errorf("untappd: malformed page token %q: %w", token, err)
+	}
+
+	return pi, nil
+}
+
+// pagination mirrors the "pagination" block returned by Untappd APIv4 list
+// endpoints.
+type pagination struct {
+	NextURL  string `json:"next_url"`
+	SinceURL string `json:"since_url"`
+	MaxID    int    `json:"max_id"`
+}
+
+// toPageInfo converts a decoded pagination block into a PageInfo, deriving
+// PageToken so it can be handed back to the same endpoint later.
+func (p pagination) toPageInfo() PageInfo {
+	pi := PageInfo{
+		NextURL: p.NextURL,
+		MaxID:   p.MaxID,
+	}
+
+	if pi.NextURL != "" {
+		if u, err := url.Parse(pi.NextURL); err == nil {
+			if offset, err := strconv.Atoi(u.Query().Get("offset")); err == nil {
+				pi.Offset = offset
+			}
+			if pi.MaxID == 0 {
+				if maxID, err := strconv.Atoi(u.Query().Get("max_id")); err == nil {
+					pi.MaxID = maxID
+				}
+			}
+		}
+	}
+
+	pi.PageToken = strconv.Itoa(pi.MaxID) + ":" + strconv.Itoa(pi.Offset)
+	return pi
+}
+
+// hasMore reports whether pi indicates that another page is available.
+func (pi PageInfo) hasMore() bool {
+	return pi.NextURL != "" || pi.MaxID != 0 || pi.Offset != 0
+}
+
+// FetchFunc retrieves a single page of items of type T, given the PageInfo
+// describing where to resume from (the zero value requests the first
+// page).  It returns the items on that page, along with the PageInfo for
+// the page that follows.
+type FetchFunc[T any] func(ctx context.Context, page PageInfo) (items []T, next PageInfo, err error)
+
+// Iterator provides uniform, buffered iteration over a paginated Untappd
+// APIv4 list endpoint, fetching additional pages on demand via a FetchFunc.
+//
+// Service types are expected to expose endpoint-specific convenience
+// constructors which wrap their existing one-page methods in a FetchFunc
+// and return an *Iterator[T] — for example, a future
+// c.User.CheckinsIter(username string, opts *CheckinOptions) *Iterator[Checkin].
+type Iterator[T any] struct {
+	fetch FetchFunc[T]
+
+	buf  []T
+	cur  T
+	page PageInfo
+	done bool
+	err  error
+}
+
+// NewIterator creates an Iterator[T] which retrieves pages using fetch,
+// starting from the first page.
+func NewIterator[T any](fetch FetchFunc[T]) *Iterator[T] {
+	return &Iterator[T]{fetch: fetch}
+}
+
+// NewIteratorFromPage creates an Iterator[T] which retrieves pages using
+// fetch, resuming from page instead of starting at the first page.  page is
+// typically obtained from a previous Iterator's PageInfo method and
+// round-tripped through its PageToken via ParsePageToken.
+func NewIteratorFromPage[T any](fetch FetchFunc[T], page PageInfo) *Iterator[T] {
+	return &Iterator[T]{fetch: fetch, page: page}
+}
+
+// Next advances the iterator to the next item, fetching additional pages
+// from the underlying FetchFunc as the current page is exhausted.  It
+// returns false once iteration is complete or an error has occurred; the
+// error, if any, is available via Err.
+func (it *Iterator[T]) Next(ctx context.Context) bool {
+	for {
+		if it.err != nil {
+			return false
+		}
+
+		if len(it.buf) > 0 {
+			it.cur, it.buf = it.buf[0], it.buf[1:]
+			return true
+		}
+
+		if it.done {
+			return false
+		}
+
+		items, next, err := it.fetch(ctx, it.page)
+		if err != nil {
+			it.err = err
+			return false
+		}
+
+		it.page = next
+		if !next.hasMore() {
+			it.done = true
+		}
+
+		if len(items) == 0 {
+			// An empty page doesn't necessarily mean iteration is over: the
+			// endpoint may still report more data via PageInfo (e.g. a
+			// max_id that skipped past a gap), so keep fetching unless
+			// hasMore also says there's nothing left.
+			if it.done {
+				return false
+			}
+			continue
+		}
+
+		it.cur, it.buf = items[0], items[1:]
+		return true
+	}
+}
+
+// Item returns the item at the iterator's current position, following a
+// call to Next which returned true.
+func (it *Iterator[T]) Item() T {
+	return it.cur
+}
+
+// Err returns the first error encountered during iteration, if any.
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// PageInfo returns the PageInfo for the most recently fetched page, which
+// callers can use to resume iteration later via PageInfo.PageToken.
+func (it *Iterator[T]) PageInfo() PageInfo {
+	return it.page
+}
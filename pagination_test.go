@@ -0,0 +1,174 @@
+package untappd
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+// TestIterator verifies that Iterator yields the concatenation of items
+// across two canned pages, then stops once the final page is exhausted.
+func TestIterator(t *testing.T) {
+	var calls int
+
+	c, done := testClient(t, func(t *testing.T, w http.ResponseWriter, r *http.Request) {
+		calls++
+
+		switch calls {
+		case 1:
+			w.Write([]byte(`{"items":[1,2],"pagination":{"max_id":2}}`))
+		case 2:
+			if s := r.URL.Query().Get("max_id"); s != "2" {
+				t.Fatalf("unexpected max_id query parameter: %q != %q", s, "2")
+			}
+			w.Write([]byte(`{"items":[3],"pagination":{"max_id":0}}`))
+		default:
+			t.Fatalf("unexpected number of calls: %d", calls)
+		}
+	})
+	defer done()
+
+	fetch := func(ctx context.Context, page PageInfo) ([]int, PageInfo, error) {
+		q := make(map[string][]string)
+		if page.MaxID != 0 {
+			q["max_id"] = []string{strconv.Itoa(page.MaxID)}
+		}
+
+		var v struct {
+			Items      []int      `json:"items"`
+			Pagination pagination `json:"pagination"`
+		}
+
+		if _, err := c.request(ctx, "GET", "foo", q, &v); err != nil {
+			return nil, PageInfo{}, err
+		}
+
+		return v.Items, v.Pagination.toPageInfo(), nil
+	}
+
+	it := NewIterator(fetch)
+
+	var got []int
+	for it.Next(context.Background()) {
+		got = append(got, it.Item())
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []int{1, 2, 3}
+	if len(got) != len(want) {
+		t.Fatalf("unexpected items: %v != %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("unexpected items: %v != %v", got, want)
+		}
+	}
+
+	if calls != 2 {
+		t.Fatalf("unexpected number of calls: %d != %d", calls, 2)
+	}
+}
+
+// TestIteratorSkipsEmptyPageWithMore verifies that Iterator keeps fetching
+// when a page comes back with zero items, as long as its PageInfo still
+// indicates more data is available, rather than stopping early.
+func TestIteratorSkipsEmptyPageWithMore(t *testing.T) {
+	pages := [][]int{
+		{},
+		{1, 2},
+	}
+
+	var calls int
+	fetch := func(ctx context.Context, page PageInfo) ([]int, PageInfo, error) {
+		items := pages[calls]
+		calls++
+
+		next := PageInfo{MaxID: 5}
+		if calls == len(pages) {
+			next = PageInfo{}
+		}
+
+		return items, next, nil
+	}
+
+	it := NewIterator(fetch)
+
+	var got []int
+	for it.Next(context.Background()) {
+		got = append(got, it.Item())
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []int{1, 2}
+	if len(got) != len(want) {
+		t.Fatalf("unexpected items: %v != %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("unexpected items: %v != %v", got, want)
+		}
+	}
+
+	if calls != len(pages) {
+		t.Fatalf("unexpected number of calls: %d != %d", calls, len(pages))
+	}
+}
+
+// TestParsePageTokenRoundTrip verifies that a PageToken produced by
+// pagination.toPageInfo can be parsed back into an equivalent PageInfo via
+// ParsePageToken, and used to resume an Iterator via NewIteratorFromPage.
+func TestParsePageTokenRoundTrip(t *testing.T) {
+	want := pagination{MaxID: 5}.toPageInfo()
+
+	got, err := ParsePageToken(want.PageToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got.MaxID != want.MaxID || got.Offset != want.Offset {
+		t.Fatalf("unexpected PageInfo: %+v != %+v", got, want)
+	}
+
+	var calls int
+	fetch := func(ctx context.Context, page PageInfo) ([]int, PageInfo, error) {
+		calls++
+
+		if page.MaxID != 5 {
+			t.Fatalf("unexpected resumed MaxID: %d != %d", page.MaxID, 5)
+		}
+
+		return []int{1}, PageInfo{}, nil
+	}
+
+	it := NewIteratorFromPage(fetch, got)
+
+	if !it.Next(context.Background()) {
+		t.Fatal(it.Err())
+	}
+	if v := it.Item(); v != 1 {
+		t.Fatalf("unexpected item: %d != %d", v, 1)
+	}
+	if it.Next(context.Background()) {
+		t.Fatal("expected iteration to be complete")
+	}
+
+	if calls != 1 {
+		t.Fatalf("unexpected number of calls: %d != %d", calls, 1)
+	}
+}
+
+// TestParsePageTokenMalformed verifies that ParsePageToken returns an error
+// for a token that isn't in the "maxid:offset" form produced by
+// pagination.toPageInfo.
+func TestParsePageTokenMalformed(t *testing.T) {
+	for _, token := range []string{"", "nope", "1:2:3", "a:b"} {
+		if _, err := ParsePageToken(token); err == nil {
+			t.Fatalf("expected error parsing malformed page token %q", token)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package untappd
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimit describes the most recently observed Untappd APIv4 rate limit
+// state, as reported by the X-Ratelimit-Limit, X-Ratelimit-Remaining, and
+// X-Ratelimit-Expired response headers.
+type RateLimit struct {
+	// Limit is the total number of requests permitted in the current
+	// rate limit window.
+	Limit int
+
+	// Remaining is the number of requests left in the current window.
+	Remaining int
+
+	// Reset is the time at which the current rate limit window expires
+	// and Remaining resets to Limit.
+	Reset time.Time
+}
+
+// rateLimitBox guards the last-seen RateLimit behind a mutex.  It is held
+// behind a pointer on Client so that shallow copies made by methods such as
+// WithAccessToken and WithRetry continue to share (and update) the same
+// observed rate limit state.
+type rateLimitBox struct {
+	mu    sync.Mutex
+	state RateLimit
+}
+
+// RateLimit returns the most recently observed rate limit state for c, as
+// populated by the headers of the last response received.  The zero value
+// is returned if no response has been received yet.
+func (c *Client) RateLimit() RateLimit {
+	c.rl.mu.Lock()
+	defer c.rl.mu.Unlock()
+
+	return c.rl.state
+}
+
+// setRateLimit records the rate limit state parsed from res, for later
+// retrieval via RateLimit.
+func (c *Client) setRateLimit(res *http.Response) {
+	rl, ok := parseRateLimit(res)
+	if !ok {
+		return
+	}
+
+	c.rl.mu.Lock()
+	defer c.rl.mu.Unlock()
+
+	c.rl.state = rl
+}
+
+// parseRateLimit parses the X-Ratelimit-* headers from res into a
+// RateLimit.  ok is false if no rate limit headers were present.
+func parseRateLimit(res *http.Response) (rl RateLimit, ok bool) {
+	limit := res.Header.Get("X-Ratelimit-Limit")
+	if limit == "" {
+		return RateLimit{}, false
+	}
+
+	rl.Limit, _ = strconv.Atoi(limit)
+	rl.Remaining, _ = strconv.Atoi(res.Header.Get("X-Ratelimit-Remaining"))
+
+	if exp := res.Header.Get("X-Ratelimit-Expired"); exp != "" {
+		if secs, err := strconv.ParseInt(exp, 10, 64); err == nil {
+			rl.Reset = time.Now().Add(time.Duration(secs) * time.Second)
+		}
+	}
+
+	return rl, true
+}
+
+// RetryPolicy configures automatic retry-with-backoff behavior for a
+// Client, used when the Untappd APIv4 responds with a rate limit error or
+// another retryable failure.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts made for a single
+	// request, including the first.  A value <= 1 disables retries.
+	MaxAttempts int
+
+	// BaseDelay is the starting delay used for exponential backoff
+	// between attempts.
+	BaseDelay time.Duration
+
+	// MaxDelay caps the computed backoff delay, including jitter.
+	MaxDelay time.Duration
+
+	// ShouldRetry reports whether a request should be retried, given the
+	// *http.Response (may be nil, if err is a transport error) and error
+	// returned by the attempt.  If nil, DefaultShouldRetry is used.
+	ShouldRetry func(res *http.Response, err error) bool
+
+	// RetryNonIdempotent allows this policy to retry non-GET requests,
+	// such as POSTs, which are not retried by default since they may not
+	// be idempotent.
+	RetryNonIdempotent bool
+}
+
+// DefaultShouldRetry is the default RetryPolicy.ShouldRetry implementation.
+// It retries on HTTP 429 (rate limit exceeded) responses and on network
+// errors (res == nil, meaning the request never reached the server). It
+// does not retry other API errors, such as invalid_user or invalid_param,
+// which are permanent and would not succeed on a later attempt.
+func DefaultShouldRetry(res *http.Response, err error) bool {
+	if res != nil && res.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+
+	return err != nil && res == nil
+}
+
+// WithRetry creates a shallow copy of Client which automatically retries
+// failed requests according to policy.
+func (c *Client) WithRetry(policy RetryPolicy) *Client {
+	cc := *c
+	cc.retry = &policy
+	return &cc
+}
+
+// backoff computes the delay before retry attempt (0-indexed), using
+// exponential backoff with jitter, capped at policy.MaxDelay.  If res
+// indicates a rate limit was hit and carries a Reset time in the future,
+// that takes precedence over the computed delay.
+func backoff(policy RetryPolicy, attempt int, res *http.Response) time.Duration {
+	if res != nil && res.StatusCode == http.StatusTooManyRequests {
+		if rl, ok := parseRateLimit(res); ok && !rl.Reset.IsZero() {
+			if d := time.Until(rl.Reset); d > 0 {
+				return d
+			}
+		}
+	}
+
+	delay := policy.BaseDelay * (1 << uint(attempt))
+	if policy.BaseDelay > 0 {
+		delay += time.Duration(rand.Int63n(int64(policy.BaseDelay)))
+	}
+	if policy.MaxDelay > 0 && delay > policy.MaxDelay {
+		delay = policy.MaxDelay
+	}
+
+	return delay
+}
+
+// sleep waits for d, or returns ctx.Err() early if ctx is canceled or its
+// deadline expires first.
+func sleep(ctx context.Context, d time.Duration) error {
+	select {
+	case <-time.After(d):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,153 @@
+package untappd
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// TestClient_requestRetriesOnRateLimit verifies that a Client configured
+// with WithRetry retries a GET request that is rate limited, until the
+// server starts returning 200 OK, and that RateLimit reflects the final
+// response's headers.
+func TestClient_requestRetriesOnRateLimit(t *testing.T) {
+	var attempts int
+
+	c, done := testClient(t, func(t *testing.T, w http.ResponseWriter, r *http.Request) {
+		attempts++
+
+		if attempts < 3 {
+			w.Header().Set("X-Ratelimit-Limit", "100")
+			w.Header().Set("X-Ratelimit-Remaining", "0")
+			w.Header().Set("X-Ratelimit-Expired", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write(apiErrJSON)
+			return
+		}
+
+		w.Header().Set("X-Ratelimit-Limit", "100")
+		w.Header().Set("X-Ratelimit-Remaining", "99")
+	})
+	defer done()
+
+	c = c.WithRetry(RetryPolicy{
+		MaxAttempts: 5,
+		BaseDelay:   1 * time.Millisecond,
+		MaxDelay:    10 * time.Millisecond,
+	})
+
+	if _, err := c.request(context.Background(), "GET", "foo", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if attempts != 3 {
+		t.Fatalf("unexpected number of attempts: %d != %d", attempts, 3)
+	}
+
+	if rl := c.RateLimit(); rl.Remaining != 99 {
+		t.Fatalf("unexpected remaining rate limit: %d != %d", rl.Remaining, 99)
+	}
+}
+
+// TestClient_requestRetryExhausted verifies that a Client configured with
+// WithRetry gives up and returns the last error after MaxAttempts is
+// reached.
+func TestClient_requestRetryExhausted(t *testing.T) {
+	var attempts int
+
+	c, done := testClient(t, func(t *testing.T, w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write(apiErrJSON)
+	})
+	defer done()
+
+	c = c.WithRetry(RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   1 * time.Millisecond,
+		MaxDelay:    10 * time.Millisecond,
+	})
+
+	if _, err := c.request(context.Background(), "GET", "foo", nil, nil); err == nil {
+		t.Fatal("expected an error, but none occurred")
+	}
+
+	if attempts != 3 {
+		t.Fatalf("unexpected number of attempts: %d != %d", attempts, 3)
+	}
+}
+
+// TestClient_requestNoRetryForPermanentError verifies that a permanent API
+// error, such as invalid_user, is not retried even when a RetryPolicy is
+// configured, since retrying would not change the outcome.
+func TestClient_requestNoRetryForPermanentError(t *testing.T) {
+	var attempts int
+
+	c, done := testClient(t, func(t *testing.T, w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusNotFound)
+		w.Write(invalidUserErrJSON)
+	})
+	defer done()
+
+	c = c.WithRetry(RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   1 * time.Millisecond,
+	})
+
+	if _, err := c.request(context.Background(), "GET", "foo", nil, nil); err == nil {
+		t.Fatal("expected an error, but none occurred")
+	}
+
+	if attempts != 1 {
+		t.Fatalf("unexpected number of attempts: %d != %d", attempts, 1)
+	}
+}
+
+// TestClient_requestNoRetryForPOST verifies that POST requests are not
+// retried by default, even when a RetryPolicy is configured.
+func TestClient_requestNoRetryForPOST(t *testing.T) {
+	var attempts int
+
+	c, done := testClient(t, func(t *testing.T, w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write(apiErrJSON)
+	})
+	defer done()
+
+	c = c.WithRetry(RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   1 * time.Millisecond,
+	})
+
+	if _, err := c.request(context.Background(), "POST", "foo", nil, nil); err == nil {
+		t.Fatal("expected an error, but none occurred")
+	}
+
+	if attempts != 1 {
+		t.Fatalf("unexpected number of attempts: %d != %d", attempts, 1)
+	}
+}
+
+// Test_backoffHonorsRateLimitReset verifies that backoff waits until the
+// rate limit's Reset time, in preference to the computed exponential delay,
+// when a 429 response with a future Reset is observed.
+func Test_backoffHonorsRateLimitReset(t *testing.T) {
+	res := &http.Response{
+		StatusCode: http.StatusTooManyRequests,
+		Header: http.Header{
+			"X-Ratelimit-Limit":     []string{"100"},
+			"X-Ratelimit-Remaining": []string{"0"},
+			"X-Ratelimit-Expired":   []string{"1"},
+		},
+	}
+
+	policy := RetryPolicy{BaseDelay: time.Millisecond, MaxDelay: time.Second}
+
+	d := backoff(policy, 0, res)
+	if d <= 500*time.Millisecond || d > time.Second+time.Second {
+		t.Fatalf("unexpected backoff duration: %v", d)
+	}
+}